@@ -6,17 +6,12 @@ import (
 	"fmt"
 	"os"
 	"strconv"
-	"syscall"
 	"time"
-	"unsafe"
 )
 
-// ioctl constants for SCSI generic (SG) operations
+// SCSI command opcodes used directly by this file.
 const (
-	SG_IO             = 0x2285
-	SCSI_SEND_DIAG    = 0x1D // SCSI "Send Diagnostic" command opcode
-	SG_DXFER_TO_DEV   = -2   // Direction of data transfer (to device)
-	SG_DXFER_FROM_DEV = 1    // Direction of data transfer (from device)
+	SCSI_SEND_DIAG = 0x1D // SCSI "Send Diagnostic" command opcode
 )
 
 // SCSI command and data lengths
@@ -58,36 +53,27 @@ var (
 	}
 )
 
-// SG_IO_Header structure for sending SCSI commands
-type SG_IO_Header struct {
-	interface_id    int32   // Identifier for the interface, typically set to 'S' for SCSI
-	dxfer_direction int32   // Data transfer direction: -2 for host to device, 1 for device to host
-	cmd_len         uint8   // Length of the SCSI command descriptor block (CDB) in bytes
-	mx_sb_len       uint8   // Maximum length of the sense buffer, used for error reporting
-	iovec_count     uint16  // Count for scatter-gather lists, set to 0 if not used
-	dxfer_len       uint32  // Length of the data to be transferred in bytes
-	dxferp          uintptr // Pointer to the data buffer for data transfer (input or output)
-	cmdp            uintptr // Pointer to the command descriptor block (CDB)
-	sbp             uintptr // Pointer to the sense buffer, which stores error information
-	timeout         uint32  // Command timeout in milliseconds
-	flags           uint32  // Additional flags for command execution (e.g., blocking, etc.)
-	pack_id         int32   // Packet ID used to track the command
-	usr_ptr         uintptr // User-defined pointer, often used for additional data tracking
-	status          uint8   // Status byte returned from the device, indicating success or error
-	masked_status   uint8   // Internal masked status, used by the driver
-	msg_status      uint8   // Message byte returned by the device
-	sb_len_wr       uint8   // Actual length of the sense buffer written by the device
-	host_status     uint16  // Host-specific status code, set by the driver
-	driver_status   uint16  // Driver-specific status code, set by the driver
-	resid           int32   // Residual byte count, indicating remaining data not transferred
-	duration        uint32  // Duration the command took to execute, in milliseconds
-	info            uint32  // Additional information about the command, such as retries or errors
-}
-
 var verbose bool
 
 func main() {
-	
+	// Dispatch to a subcommand if one was given, e.g. `logsense`.
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "logsense":
+			runLogSense(os.Args[2:])
+			return
+		case "tapealert":
+			runTapeAlert(os.Args[2:])
+			return
+		case "mam":
+			runMAM(os.Args[2:])
+			return
+		case "serve":
+			runServe(os.Args[2:])
+			return
+		}
+	}
+
 	// Define and parse the flags
 	flag.BoolVar(&verbose, "verbose", false, "Enable verbose output")
 	flag.Usage = func() {
@@ -112,18 +98,18 @@ func main() {
 
 	device := flag.Args()[0]
 
-	file, err := os.OpenFile(device, os.O_RDWR, 0666)
+	transport, err := OpenTransport(device)
 	if err != nil {
-		fmt.Printf("Failed to open device %s: %v\n", device, err)
+		fmt.Printf("%v\n", err)
 		os.Exit(1)
 	}
-	defer file.Close()
+	defer transport.Close()
 
 	// Step 1: Send SEND DIAGNOSTIC command
 	if verbose {
 		fmt.Printf("Sending SEND DIAGNOSTIC command with cmd=%s and dataOut=%s\n", formatBytes(sendDiagnosticCmd[:]), formatBytes(sendDiagnosticDataOut[:]))
 	}
-	if err := sendScsiCommand(file, sendDiagnosticCmd[:], sendDiagnosticDataOut[:], nil, SG_DXFER_TO_DEV, 60*time.Second); err != nil {
+	if err := sendScsiCommand(transport, sendDiagnosticCmd[:], sendDiagnosticDataOut[:], nil, 60*time.Second); err != nil {
 		fmt.Printf("Failed to send SEND DIAGNOSTIC command: %v\n", err)
 		os.Exit(1)
 	}
@@ -136,7 +122,7 @@ func main() {
 	if verbose {
 		fmt.Printf("Sending RECEIVE DIAGNOSTIC command with cmd=%s\n", formatBytes(receiveDiagnosticCmd[:]))
 	}
-	if err := sendScsiCommand(file, receiveDiagnosticCmd[:], nil, dataIn, SG_DXFER_FROM_DEV, 10*time.Second); err != nil {
+	if err := sendScsiCommand(transport, receiveDiagnosticCmd[:], nil, dataIn, 10*time.Second); err != nil {
 		fmt.Printf("Failed to send RECEIVE DIAGNOSTIC command: %v\n", err)
 		os.Exit(1)
 	}
@@ -182,51 +168,6 @@ func formatBytes(data []byte) string {
 	return formatted
 }
 
-// sendScsiCommand sends a SCSI command to a device using the SG_IO ioctl
-func sendScsiCommand(file *os.File, cmd []byte, dataOut []byte, dataIn []byte, direction int32, timeout time.Duration) error {
-	// Allocate sense buffer for error reporting
-	sense := make([]byte, 32)
-
-	// Prepare the SG_IO_Header
-	header := SG_IO_Header{
-		interface_id:    'S',
-		dxfer_direction: direction,
-		cmd_len:         uint8(len(cmd)),
-		mx_sb_len:       uint8(len(sense)),
-		dxfer_len:       uint32(len(dataOut) + len(dataIn)),
-		cmdp:            uintptr(unsafe.Pointer(&cmd[0])),
-		sbp:             uintptr(unsafe.Pointer(&sense[0])),
-		timeout:         uint32(timeout / time.Millisecond), // Convert timeout to milliseconds
-	}
-
-	// Set up data transfer pointers if needed
-	if len(dataOut) > 0 {
-		header.dxferp = uintptr(unsafe.Pointer(&dataOut[0]))
-	}
-	if len(dataIn) > 0 {
-		header.dxferp = uintptr(unsafe.Pointer(&dataIn[0]))
-	}
-
-	// Execute ioctl command
-	if verbose {
-		fmt.Printf("Executing ioctl with cmd=%s, dataOut=%s, dataInLen=%d\n", formatBytes(cmd), formatBytes(dataOut), len(dataIn))
-	}
-	if err := ioctl(int(file.Fd()), SG_IO, uintptr(unsafe.Pointer(&header))); err != nil {
-		return fmt.Errorf("command failed: %w", err)
-	}
-
-	// Check for SCSI status success and output sense data if failure occurs
-	if header.status != 0 {
-		if verbose {
-			fmt.Printf("Command failed with status: %d\n", header.status)
-			fmt.Printf("Sense data: %s\n", hex.EncodeToString(sense))
-		}
-		return fmt.Errorf("command failed with status: %d", header.status)
-	}
-
-	return nil
-}
-
 // extractAndConvertTemperature extracts bytes 22-29, converts each pair of ASCII hex characters, and returns the decoded hex string
 func extractAndConvertTemperature(data []byte) (string, error) {
 	if len(data) < 30 {
@@ -251,12 +192,3 @@ func extractAndConvertTemperature(data []byte) (string, error) {
 func hexToDecimal(hexStr string) (int64, error) {
 	return strconv.ParseInt(hexStr, 16, 64)
 }
-
-// ioctl function to send commands to the device
-func ioctl(fd int, request int, arg uintptr) error {
-	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(request), arg)
-	if errno != 0 {
-		return errno
-	}
-	return nil
-}
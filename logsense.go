@@ -0,0 +1,336 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+// SPC LOG SENSE command opcode and well-known page codes used by this tool.
+const (
+	SCSI_LOG_SENSE = 0x4D // Operation Code: LOG SENSE command (0x4D)
+
+	LOG_PAGE_TEMPERATURE = 0x0D // Temperature log page (current + reference temperature)
+	LOG_PAGE_IE          = 0x2F // Informational Exceptions log page (SMART-equivalent ASC/ASCQ)
+	LOG_PAGE_SEQ_ACCESS  = 0x0C // Sequential-Access Device log page (read/write bytes)
+	LOG_PAGE_TAPE_USAGE  = 0x30 // Tape usage vendor log page (load count, etc.)
+	LOG_PAGE_TAPE_CAP    = 0x31 // Tape capacity vendor log page (partition MB read/written)
+	LOG_PAGE_DATA_COMPR  = 0x32 // Data compression vendor log page
+
+	LOG_SENSE_CMD_LEN = 10 // LOG SENSE CDB is 10 bytes
+
+	// LOG_SENSE_ALLOC_LEN must cover the largest page this tool reads. A
+	// full TapeAlert page (0x2E) is 64 parameters * 5 bytes + a 4-byte
+	// header = 324 bytes, so 252 silently truncated the high flags
+	// (including "Snapped tape", 0x3C); 0x200 leaves headroom.
+	LOG_SENSE_ALLOC_LEN = 0x200
+)
+
+// logParameter is one decoded TLV entry from a log page: a 2-byte parameter
+// code, a control byte, a 1-byte length, and the value bytes themselves.
+type logParameter struct {
+	Code    uint16
+	Control byte
+	Value   []byte
+}
+
+// TemperaturePage is the decoded form of log page 0x0D.
+type TemperaturePage struct {
+	CurrentCelsius   int
+	ReferenceCelsius int
+}
+
+// InformationalExceptionsPage is the decoded form of log page 0x2F.
+type InformationalExceptionsPage struct {
+	ASC            byte
+	ASCQ           byte
+	MostRecentTemp int
+}
+
+// TapeUsagePage is the decoded form of the vendor Tape Usage log page
+// (0x30): megabytes read/written and the associated error counters.
+type TapeUsagePage struct {
+	MegabytesWritten int64
+	MegabytesRead    int64
+	WriteErrorCount  int64
+	ReadErrorCount   int64
+}
+
+// TapeCapacityPage is the decoded form of the vendor Tape Capacity log page
+// (0x31): megabytes read/written to the current partition.
+type TapeCapacityPage struct {
+	PartitionMegabytesWritten int64
+	PartitionMegabytesRead    int64
+}
+
+// DataCompressionPage is the decoded form of the vendor Data Compression log
+// page (0x32): the drive's current read/write compression ratios.
+type DataCompressionPage struct {
+	WriteCompressionRatio int64
+	ReadCompressionRatio  int64
+}
+
+// SequentialAccessPage is the decoded form of log page 0x0C.
+type SequentialAccessPage struct {
+	TotalBytesWritten int64
+	TotalBytesRead    int64
+}
+
+// pageNameToCode maps the --page flag values accepted by the logsense
+// subcommand to their LOG SENSE page codes.
+var pageNameToCode = map[string]byte{
+	"temperature":     LOG_PAGE_TEMPERATURE,
+	"ie":              LOG_PAGE_IE,
+	"sequential":      LOG_PAGE_SEQ_ACCESS,
+	"tapeusage":       LOG_PAGE_TAPE_USAGE,
+	"tapecapacity":    LOG_PAGE_TAPE_CAP,
+	"datacompression": LOG_PAGE_DATA_COMPR,
+}
+
+// runLogSense implements the `logsense` subcommand: it issues LOG SENSE for
+// the requested page, decodes it, and prints the result.
+func runLogSense(args []string) {
+	fs := flag.NewFlagSet("logsense", flag.ExitOnError)
+	page := fs.String("page", "temperature", "Log page to read: temperature, ie, sequential, tapeusage, tapecapacity, datacompression")
+	jsonOut := fs.Bool("json", false, "Print the decoded page as JSON")
+	fs.BoolVar(&verbose, "verbose", verbose, "Enable verbose output")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s logsense [--page=temperature|ie|sequential|tapeusage|tapecapacity|datacompression] [--json] <scsi_device>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	device := fs.Arg(0)
+
+	pageCode, ok := pageNameToCode[*page]
+	if !ok {
+		fmt.Printf("Unknown log page %q\n", *page)
+		os.Exit(1)
+	}
+
+	transport, err := OpenTransport(device)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+	defer transport.Close()
+
+	params, err := readLogPage(transport, pageCode)
+	if err != nil {
+		fmt.Printf("Failed to read log page 0x%02X: %v\n", pageCode, err)
+		os.Exit(1)
+	}
+
+	var result interface{}
+	switch pageCode {
+	case LOG_PAGE_TEMPERATURE:
+		result = decodeTemperaturePage(params)
+	case LOG_PAGE_IE:
+		result = decodeIEPage(params)
+	case LOG_PAGE_SEQ_ACCESS:
+		result = decodeSequentialAccessPage(params)
+	case LOG_PAGE_TAPE_USAGE:
+		result = decodeTapeUsagePage(params)
+	case LOG_PAGE_TAPE_CAP:
+		result = decodeTapeCapacityPage(params)
+	case LOG_PAGE_DATA_COMPR:
+		result = decodeDataCompressionPage(params)
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(result)
+		return
+	}
+
+	printLogPage(*page, result)
+}
+
+// buildLogSenseCmd builds the 10-byte LOG SENSE CDB for the given page code.
+// Bits PC=01 (current values) and SP=0 (no saving) are used, matching the
+// common case for monitoring tools.
+func buildLogSenseCmd(pageCode byte) [LOG_SENSE_CMD_LEN]byte {
+	return [LOG_SENSE_CMD_LEN]byte{
+		SCSI_LOG_SENSE,                   // Operation Code: LOG SENSE (0x4D)
+		0x00,                             // SP=0, PPC=0
+		0x40 | (pageCode & 0x3F),         // PC=01 (current cumulative values), page code
+		0x00,                             // Subpage code
+		0x00,                             // Reserved
+		0x00,                             // Reserved
+		0x00,                             // Parameter pointer (MSB)
+		0x00,                             // Parameter pointer (LSB)
+		byte(LOG_SENSE_ALLOC_LEN >> 8),   // Allocation length (MSB)
+		byte(LOG_SENSE_ALLOC_LEN & 0xFF), // Allocation length (LSB)
+	}
+}
+
+// readLogPage issues LOG SENSE for pageCode and walks the returned
+// log-parameter TLVs, returning them as a slice for the page-specific
+// decoders to interpret.
+func readLogPage(t Transport, pageCode byte) ([]logParameter, error) {
+	cmd := buildLogSenseCmd(pageCode)
+	dataIn := make([]byte, LOG_SENSE_ALLOC_LEN)
+
+	if verbose {
+		fmt.Printf("Sending LOG SENSE command with cmd=%s\n", formatBytes(cmd[:]))
+	}
+	if err := sendScsiCommand(t, cmd[:], nil, dataIn, 10*time.Second); err != nil {
+		return nil, fmt.Errorf("LOG SENSE failed: %w", err)
+	}
+
+	if len(dataIn) < 4 {
+		return nil, fmt.Errorf("log page response too short")
+	}
+	pageLength := int(dataIn[2])<<8 | int(dataIn[3])
+	if 4+pageLength > len(dataIn) {
+		pageLength = len(dataIn) - 4
+	}
+
+	return parseLogParameters(dataIn[4 : 4+pageLength]), nil
+}
+
+// parseLogParameters walks a log page's parameter list, where each entry is
+// a 2-byte parameter code, a control byte, a 1-byte length, and that many
+// value bytes.
+func parseLogParameters(data []byte) []logParameter {
+	var params []logParameter
+	for i := 0; i+4 <= len(data); {
+		code := uint16(data[i])<<8 | uint16(data[i+1])
+		control := data[i+2]
+		length := int(data[i+3])
+		start := i + 4
+		end := start + length
+		if end > len(data) {
+			break
+		}
+		params = append(params, logParameter{Code: code, Control: control, Value: data[start:end]})
+		i = end
+	}
+	return params
+}
+
+// paramValueInt interprets a log parameter's value bytes as a big-endian
+// unsigned integer.
+func paramValueInt(p logParameter) int64 {
+	var v int64
+	for _, b := range p.Value {
+		v = v<<8 | int64(b)
+	}
+	return v
+}
+
+func findParam(params []logParameter, code uint16) (logParameter, bool) {
+	for _, p := range params {
+		if p.Code == code {
+			return p, true
+		}
+	}
+	return logParameter{}, false
+}
+
+func decodeTemperaturePage(params []logParameter) TemperaturePage {
+	var tp TemperaturePage
+	if p, ok := findParam(params, 0x0000); ok {
+		tp.CurrentCelsius = int(paramValueInt(p))
+	}
+	if p, ok := findParam(params, 0x0001); ok {
+		tp.ReferenceCelsius = int(paramValueInt(p))
+	}
+	return tp
+}
+
+func decodeIEPage(params []logParameter) InformationalExceptionsPage {
+	var ie InformationalExceptionsPage
+	if p, ok := findParam(params, 0x0000); ok && len(p.Value) >= 2 {
+		ie.ASC = p.Value[0]
+		ie.ASCQ = p.Value[1]
+		if len(p.Value) >= 3 {
+			ie.MostRecentTemp = int(p.Value[2])
+		}
+	}
+	return ie
+}
+
+func decodeSequentialAccessPage(params []logParameter) SequentialAccessPage {
+	var sp SequentialAccessPage
+	if p, ok := findParam(params, 0x0002); ok {
+		sp.TotalBytesWritten = paramValueInt(p)
+	}
+	if p, ok := findParam(params, 0x0005); ok {
+		sp.TotalBytesRead = paramValueInt(p)
+	}
+	return sp
+}
+
+func decodeTapeUsagePage(params []logParameter) TapeUsagePage {
+	var tu TapeUsagePage
+	for _, p := range params {
+		switch p.Code {
+		case 0x0002:
+			tu.MegabytesWritten = paramValueInt(p)
+		case 0x0003:
+			tu.MegabytesRead = paramValueInt(p)
+		case 0x0006:
+			tu.WriteErrorCount = paramValueInt(p)
+		case 0x0007:
+			tu.ReadErrorCount = paramValueInt(p)
+		}
+	}
+	return tu
+}
+
+func decodeTapeCapacityPage(params []logParameter) TapeCapacityPage {
+	var tc TapeCapacityPage
+	if p, ok := findParam(params, 0x0002); ok {
+		tc.PartitionMegabytesWritten = paramValueInt(p)
+	}
+	if p, ok := findParam(params, 0x0003); ok {
+		tc.PartitionMegabytesRead = paramValueInt(p)
+	}
+	return tc
+}
+
+func decodeDataCompressionPage(params []logParameter) DataCompressionPage {
+	var dc DataCompressionPage
+	if p, ok := findParam(params, 0x0000); ok {
+		dc.ReadCompressionRatio = paramValueInt(p)
+	}
+	if p, ok := findParam(params, 0x0001); ok {
+		dc.WriteCompressionRatio = paramValueInt(p)
+	}
+	return dc
+}
+
+func printLogPage(page string, result interface{}) {
+	switch v := result.(type) {
+	case TemperaturePage:
+		fmt.Printf("Current temperature:   %d°C\n", v.CurrentCelsius)
+		fmt.Printf("Reference temperature: %d°C\n", v.ReferenceCelsius)
+	case InformationalExceptionsPage:
+		fmt.Printf("ASC/ASCQ: 0x%02X/0x%02X\n", v.ASC, v.ASCQ)
+		fmt.Printf("Most recent temperature reading: %d°C\n", v.MostRecentTemp)
+	case SequentialAccessPage:
+		fmt.Printf("Total bytes written: %d\n", v.TotalBytesWritten)
+		fmt.Printf("Total bytes read:    %d\n", v.TotalBytesRead)
+	case TapeUsagePage:
+		fmt.Printf("Megabytes written: %d\n", v.MegabytesWritten)
+		fmt.Printf("Megabytes read:    %d\n", v.MegabytesRead)
+		fmt.Printf("Write errors:      %d\n", v.WriteErrorCount)
+		fmt.Printf("Read errors:       %d\n", v.ReadErrorCount)
+	case TapeCapacityPage:
+		fmt.Printf("Partition megabytes written: %d\n", v.PartitionMegabytesWritten)
+		fmt.Printf("Partition megabytes read:    %d\n", v.PartitionMegabytesRead)
+	case DataCompressionPage:
+		fmt.Printf("Write compression ratio: %d\n", v.WriteCompressionRatio)
+		fmt.Printf("Read compression ratio:  %d\n", v.ReadCompressionRatio)
+	default:
+		fmt.Printf("Unhandled page %q\n", page)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLogParameters(t *testing.T) {
+	// Two parameters: code 0x0000 control 0x00 len 2 value {0x01,0x02},
+	// then code 0x0001 control 0x00 len 1 value {0xFF}.
+	data := []byte{0x00, 0x00, 0x00, 0x02, 0x01, 0x02, 0x00, 0x01, 0x00, 0x01, 0xFF}
+
+	got := parseLogParameters(data)
+	want := []logParameter{
+		{Code: 0x0000, Control: 0x00, Value: []byte{0x01, 0x02}},
+		{Code: 0x0001, Control: 0x00, Value: []byte{0xFF}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseLogParameters() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseLogParametersTruncated(t *testing.T) {
+	// A parameter claiming a 5-byte value but only 2 bytes follow; it must be
+	// dropped rather than read out of bounds.
+	data := []byte{0x00, 0x00, 0x00, 0x05, 0xAA, 0xBB}
+	got := parseLogParameters(data)
+	if len(got) != 0 {
+		t.Fatalf("parseLogParameters() = %+v, want no parameters", got)
+	}
+}
+
+func TestDecodeIEPage(t *testing.T) {
+	// ASC=0x5D, ASCQ=0x00, most recent temperature=42.
+	params := []logParameter{
+		{Code: 0x0000, Value: []byte{0x5D, 0x00, 42}},
+	}
+	got := decodeIEPage(params)
+	want := InformationalExceptionsPage{ASC: 0x5D, ASCQ: 0x00, MostRecentTemp: 42}
+	if got != want {
+		t.Fatalf("decodeIEPage() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeIEPageShortValue(t *testing.T) {
+	// Only ASC/ASCQ present, no temperature byte.
+	params := []logParameter{
+		{Code: 0x0000, Value: []byte{0x11, 0x22}},
+	}
+	got := decodeIEPage(params)
+	want := InformationalExceptionsPage{ASC: 0x11, ASCQ: 0x22, MostRecentTemp: 0}
+	if got != want {
+		t.Fatalf("decodeIEPage() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeTapeCapacityPage(t *testing.T) {
+	params := []logParameter{
+		{Code: 0x0002, Value: []byte{0x00, 0x64}}, // 100
+		{Code: 0x0003, Value: []byte{0x00, 0x32}}, // 50
+	}
+	got := decodeTapeCapacityPage(params)
+	want := TapeCapacityPage{PartitionMegabytesWritten: 100, PartitionMegabytesRead: 50}
+	if got != want {
+		t.Fatalf("decodeTapeCapacityPage() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeDataCompressionPage(t *testing.T) {
+	params := []logParameter{
+		{Code: 0x0000, Value: []byte{0x02}},
+		{Code: 0x0001, Value: []byte{0x03}},
+	}
+	got := decodeDataCompressionPage(params)
+	want := DataCompressionPage{ReadCompressionRatio: 2, WriteCompressionRatio: 3}
+	if got != want {
+		t.Fatalf("decodeDataCompressionPage() = %+v, want %+v", got, want)
+	}
+}
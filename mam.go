@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+// SSC READ ATTRIBUTE / WRITE ATTRIBUTE opcodes and the service action used to
+// request the whole attribute list.
+const (
+	SCSI_READ_ATTRIBUTE  = 0x8C
+	SCSI_WRITE_ATTRIBUTE = 0x8D
+
+	READ_ATTRIBUTE_VALUES = 0x00 // Service action: return current attribute values
+
+	READ_ATTRIBUTE_CMD_LEN   = 16
+	WRITE_ATTRIBUTE_CMD_LEN  = 16
+	READ_ATTRIBUTE_ALLOC_LEN = 8192
+)
+
+// Well-known MAM attribute IDs defined by SSC-3.
+const (
+	MAM_REMAINING_CAPACITY     = 0x0000
+	MAM_NATIVE_CAPACITY        = 0x0001
+	MAM_LOAD_COUNT             = 0x0224
+	MAM_LIFETIME_MB_WRITTEN    = 0x0220
+	MAM_LIFETIME_MB_READ       = 0x0221
+	MAM_ASSIGNING_ORGANIZATION = 0x0003
+	MAM_MANUFACTURER           = 0x0400
+	MAM_SERIAL_NUMBER          = 0x0401
+	MAM_TEXT_LOCALIZATION_ID   = 0x0800
+	MAM_USER_MEDIUM_TEXT_LABEL = 0x0801
+)
+
+// mamAttributeFormat is the format code in an attribute's 1-byte format
+// field, per SSC-3.
+type mamAttributeFormat byte
+
+const (
+	mamFormatBinary mamAttributeFormat = 0
+	mamFormatASCII  mamAttributeFormat = 1
+	mamFormatText   mamAttributeFormat = 2
+)
+
+// MAMAttribute is one decoded entry from a READ ATTRIBUTE response: a 2-byte
+// ID, a 1-byte format code, and the value bytes.
+type MAMAttribute struct {
+	ID     uint16 `json:"id"`
+	Format string `json:"format"`
+	Value  string `json:"value"`
+}
+
+// mamAttributeNames gives human-readable names for the attributes this tool
+// knows about; unrecognized IDs are still printed, just unnamed.
+var mamAttributeNames = map[uint16]string{
+	MAM_REMAINING_CAPACITY:     "Remaining Capacity In Partition",
+	MAM_NATIVE_CAPACITY:        "Maximum Capacity In Partition",
+	MAM_LOAD_COUNT:             "Load Count",
+	MAM_LIFETIME_MB_WRITTEN:    "Total MBytes Written On This Cartridge",
+	MAM_LIFETIME_MB_READ:       "Total MBytes Read On This Cartridge",
+	MAM_ASSIGNING_ORGANIZATION: "Assigning Organization",
+	MAM_MANUFACTURER:           "Medium Manufacturer",
+	MAM_SERIAL_NUMBER:          "Medium Serial Number",
+	MAM_TEXT_LOCALIZATION_ID:   "Text Localization Identifier",
+	MAM_USER_MEDIUM_TEXT_LABEL: "User Medium Text Label",
+}
+
+// runMAM implements the `mam` subcommand: read (and optionally write)
+// Medium Auxiliary Memory attributes via READ/WRITE ATTRIBUTE.
+func runMAM(args []string) {
+	fs := flag.NewFlagSet("mam", flag.ExitOnError)
+	jsonOut := fs.Bool("json", false, "Print attributes as JSON")
+	writeID := fs.Uint("write", 0, "Attribute ID to write (requires --value); 0 means read-only mode")
+	writeValue := fs.String("value", "", "ASCII value to write for --write")
+	fs.BoolVar(&verbose, "verbose", verbose, "Enable verbose output")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s mam [--json] [--write=<id> --value=<text>] <scsi_device>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	device := fs.Arg(0)
+
+	transport, err := OpenTransport(device)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+	defer transport.Close()
+
+	if *writeID != 0 {
+		if err := writeAttribute(transport, uint16(*writeID), *writeValue); err != nil {
+			fmt.Printf("Failed to write attribute 0x%04X: %v\n", *writeID, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote attribute 0x%04X.\n", *writeID)
+		return
+	}
+
+	attrs, err := readAttributes(transport)
+	if err != nil {
+		fmt.Printf("Failed to read MAM attributes: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(attrs)
+		return
+	}
+
+	for _, a := range attrs {
+		name := mamAttributeNames[a.ID]
+		if name == "" {
+			name = fmt.Sprintf("Attribute 0x%04X", a.ID)
+		}
+		fmt.Printf("%-40s %s\n", name, a.Value)
+	}
+}
+
+// buildReadAttributeCmd builds the 16-byte READ ATTRIBUTE CDB requesting
+// current values starting at firstAttribute.
+func buildReadAttributeCmd(firstAttribute uint16) [READ_ATTRIBUTE_CMD_LEN]byte {
+	return [READ_ATTRIBUTE_CMD_LEN]byte{
+		SCSI_READ_ATTRIBUTE,         // Operation Code: READ ATTRIBUTE (0x8C)
+		READ_ATTRIBUTE_VALUES,       // Service Action: attribute values
+		0x00,                        // Reserved
+		0x00,                        // Reserved
+		0x00,                        // Reserved
+		0x00,                        // Reserved
+		0x00,                        // Volume number (0 = current)
+		0x00,                        // Reserved
+		byte(firstAttribute >> 8),   // First attribute ID (MSB)
+		byte(firstAttribute & 0xFF), // First attribute ID (LSB)
+		byte(READ_ATTRIBUTE_ALLOC_LEN >> 24),
+		byte(READ_ATTRIBUTE_ALLOC_LEN >> 16),
+		byte(READ_ATTRIBUTE_ALLOC_LEN >> 8),
+		byte(READ_ATTRIBUTE_ALLOC_LEN & 0xFF),
+		0x00, // Reserved
+		0x00, // Control byte
+	}
+}
+
+// readAttributes issues READ ATTRIBUTE and decodes the returned 4-byte
+// header plus attribute list, where each entry is a 2-byte ID, a 1-byte
+// format code (0=binary, 1=ASCII, 2=text), a 2-byte length, and the value.
+func readAttributes(t Transport) ([]MAMAttribute, error) {
+	cmd := buildReadAttributeCmd(0)
+	dataIn := make([]byte, READ_ATTRIBUTE_ALLOC_LEN)
+
+	if verbose {
+		fmt.Printf("Sending READ ATTRIBUTE command with cmd=%s\n", formatBytes(cmd[:]))
+	}
+	if err := sendScsiCommand(t, cmd[:], nil, dataIn, 30*time.Second); err != nil {
+		return nil, fmt.Errorf("READ ATTRIBUTE failed: %w", err)
+	}
+
+	if len(dataIn) < 4 {
+		return nil, fmt.Errorf("attribute list response too short")
+	}
+	listLength := int(dataIn[0])<<24 | int(dataIn[1])<<16 | int(dataIn[2])<<8 | int(dataIn[3])
+	if 4+listLength > len(dataIn) {
+		listLength = len(dataIn) - 4
+	}
+
+	return parseMAMAttributes(dataIn[4 : 4+listLength]), nil
+}
+
+// parseMAMAttributes walks an attribute list (as returned by READ ATTRIBUTE,
+// minus its 4-byte header), decoding each entry's 2-byte ID, 1-byte format
+// code, and 2-byte length before slicing out its value. A truncated trailing
+// entry is dropped rather than causing an error.
+func parseMAMAttributes(data []byte) []MAMAttribute {
+	var attrs []MAMAttribute
+	for i := 0; i+5 <= len(data); {
+		id := uint16(data[i])<<8 | uint16(data[i+1])
+		format := mamAttributeFormat(data[i+2])
+		length := int(data[i+3])<<8 | int(data[i+4])
+		start := i + 5
+		end := start + length
+		if end > len(data) {
+			break
+		}
+		attrs = append(attrs, MAMAttribute{ID: id, Format: formatName(format), Value: formatAttributeValue(format, data[start:end])})
+		i = end
+	}
+	return attrs
+}
+
+func formatName(f mamAttributeFormat) string {
+	switch f {
+	case mamFormatASCII:
+		return "ascii"
+	case mamFormatText:
+		return "text"
+	default:
+		return "binary"
+	}
+}
+
+// formatAttributeValue renders an attribute's raw value bytes according to
+// its format code: ASCII/text attributes print as trimmed strings, binary
+// attributes print as a big-endian integer.
+func formatAttributeValue(format mamAttributeFormat, value []byte) string {
+	switch format {
+	case mamFormatASCII, mamFormatText:
+		return string(value)
+	default:
+		var v uint64
+		for _, b := range value {
+			v = v<<8 | uint64(b)
+		}
+		return fmt.Sprintf("%d", v)
+	}
+}
+
+// buildWriteAttributeCmd builds the 16-byte WRITE ATTRIBUTE CDB for a
+// parameter list of the given length.
+func buildWriteAttributeCmd(paramListLength uint32) [WRITE_ATTRIBUTE_CMD_LEN]byte {
+	return [WRITE_ATTRIBUTE_CMD_LEN]byte{
+		SCSI_WRITE_ATTRIBUTE, // Operation Code: WRITE ATTRIBUTE (0x8D)
+		0x01,                 // WTC (Write Through Cache) bit set
+		0x00,                 // Reserved
+		0x00,                 // Reserved
+		0x00,                 // Reserved
+		0x00,                 // Reserved
+		0x00,                 // Volume number (0 = current)
+		0x00,                 // Reserved
+		0x00,                 // Reserved
+		0x00,                 // Reserved
+		byte(paramListLength >> 24),
+		byte(paramListLength >> 16),
+		byte(paramListLength >> 8),
+		byte(paramListLength & 0xFF),
+		0x00, // Reserved
+		0x00, // Control byte
+	}
+}
+
+// writeAttribute issues WRITE ATTRIBUTE for a single host-writable
+// attribute, such as Text Localization Identifier or User Medium Text
+// Label, encoding value as ASCII.
+func writeAttribute(t Transport, id uint16, value string) error {
+	entry := make([]byte, 5+len(value))
+	entry[0] = byte(id >> 8)
+	entry[1] = byte(id & 0xFF)
+	entry[2] = byte(mamFormatASCII)
+	entry[3] = byte(len(value) >> 8)
+	entry[4] = byte(len(value) & 0xFF)
+	copy(entry[5:], value)
+
+	paramList := make([]byte, 4+len(entry))
+	listLength := uint32(len(entry))
+	paramList[0] = byte(listLength >> 24)
+	paramList[1] = byte(listLength >> 16)
+	paramList[2] = byte(listLength >> 8)
+	paramList[3] = byte(listLength & 0xFF)
+	copy(paramList[4:], entry)
+
+	cmd := buildWriteAttributeCmd(uint32(len(paramList)))
+	if verbose {
+		fmt.Printf("Sending WRITE ATTRIBUTE command with cmd=%s\n", formatBytes(cmd[:]))
+	}
+	return sendScsiCommand(t, cmd[:], paramList, nil, 30*time.Second)
+}
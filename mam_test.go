@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestParseMAMAttributes(t *testing.T) {
+	data := []byte{
+		0x00, 0x00, 0x00, 0x00, 0x02, 0x12, 0x34, // binary: Remaining Capacity = 0x1234
+		0x04, 0x00, 0x01, 0x00, 0x03, 'f', 'o', 'o', // ASCII: Manufacturer = "foo"
+	}
+
+	attrs := parseMAMAttributes(data)
+	if len(attrs) != 2 {
+		t.Fatalf("parseMAMAttributes() returned %d attributes, want 2: %+v", len(attrs), attrs)
+	}
+	if attrs[0].ID != MAM_REMAINING_CAPACITY || attrs[0].Format != "binary" || attrs[0].Value != "4660" {
+		t.Errorf("attrs[0] = %+v, want ID=0x0000 Format=binary Value=4660", attrs[0])
+	}
+	if attrs[1].Format != "ascii" || attrs[1].Value != "foo" {
+		t.Errorf("attrs[1] = %+v, want Format=ascii Value=foo", attrs[1])
+	}
+}
+
+func TestParseMAMAttributesTruncatedEntryDropped(t *testing.T) {
+	data := []byte{
+		0x00, 0x00, 0x00, 0x00, 0x02, 0x12, 0x34, // one full binary attribute
+		0x04, 0x01, 0x00, 0x00, 0x05, 'a', // truncated: length 5 but only 1 byte of value
+	}
+
+	attrs := parseMAMAttributes(data)
+	if len(attrs) != 1 {
+		t.Fatalf("parseMAMAttributes() returned %d attributes, want 1: %+v", len(attrs), attrs)
+	}
+	if attrs[0].ID != MAM_REMAINING_CAPACITY {
+		t.Errorf("attrs[0].ID = 0x%04X, want 0x0000", attrs[0].ID)
+	}
+}
+
+func TestParseMAMAttributesEmpty(t *testing.T) {
+	if attrs := parseMAMAttributes(nil); attrs != nil {
+		t.Fatalf("parseMAMAttributes(nil) = %+v, want nil", attrs)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+func TestParseFixedSenseData(t *testing.T) {
+	sense := make([]byte, 18)
+	sense[0] = 0x70  // response code: fixed, current
+	sense[2] = 0x06  // sense key: Unit Attention
+	sense[12] = 0x29 // ASC
+	sense[13] = 0x00 // ASCQ
+	sense[14] = 0x01 // FRU
+
+	info, err := parseSenseData(sense)
+	if err != nil {
+		t.Fatalf("parseSenseData() error = %v", err)
+	}
+	if info.SenseKey != 0x06 || info.ASC != 0x29 || info.ASCQ != 0x00 || info.FRU != 0x01 {
+		t.Fatalf("parseSenseData() = %+v, want SenseKey=0x06 ASC=0x29 ASCQ=0x00 FRU=0x01", info)
+	}
+}
+
+func TestParseDescriptorSenseData(t *testing.T) {
+	// 8-byte header (sense key 0x03, ASC/ASCQ 0x11/0x00) followed by one
+	// Information descriptor (type 0x00, 8-byte value, Information=0x1234).
+	sense := []byte{
+		0x72, 0x03, 0x11, 0x00, 0x00, 0x00, 0x00, 0x0A,
+		0x00, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x12, 0x34,
+	}
+
+	info, err := parseSenseData(sense)
+	if err != nil {
+		t.Fatalf("parseSenseData() error = %v", err)
+	}
+	if info.SenseKey != 0x03 || info.ASC != 0x11 || info.ASCQ != 0x00 {
+		t.Fatalf("parseSenseData() = %+v, want SenseKey=0x03 ASC=0x11 ASCQ=0x00", info)
+	}
+	if info.Information != 0x1234 {
+		t.Fatalf("parseSenseData() Information = 0x%X, want 0x1234", info.Information)
+	}
+}
+
+func TestParseSenseDataUnrecognizedResponseCode(t *testing.T) {
+	if _, err := parseSenseData([]byte{0x00}); err == nil {
+		t.Fatal("parseSenseData() expected an error for an unrecognized response code, got nil")
+	}
+}
+
+func TestErrorMessageFallsBackForUnknownASCQ(t *testing.T) {
+	err := &Error{Info: SenseInfo{SenseKey: 0x03, ASC: 0xAB, ASCQ: 0xCD}}
+	want := "Medium Error — ASC/ASCQ 0xAB/0xCD"
+	if got := err.Error(); got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+}
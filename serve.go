@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DeviceConfig describes one SCSI device to poll, as read from the `serve`
+// subcommand's config file.
+type DeviceConfig struct {
+	Path            string `json:"path"`
+	IntervalSeconds int    `json:"interval_seconds"`
+}
+
+// ServeConfig is the top-level shape of the `serve` config file.
+type ServeConfig struct {
+	Devices []DeviceConfig `json:"devices"`
+}
+
+// deviceMetrics holds the most recently polled values for one device. It is
+// updated by that device's poll loop and read by the /metrics handler.
+type deviceMetrics struct {
+	serial             string
+	maxTemperatureC    float64
+	lifetimeMBWritten  int64
+	lifetimeMBRead     int64
+	tapeAlertFlags     []TapeAlertFlag
+	commandErrorCounts map[string]int64
+}
+
+// deviceCollector owns the poll loop and mutex for a single device so
+// concurrent scrapes never collide on the same SG device mid-command.
+type deviceCollector struct {
+	config  DeviceConfig
+	mu      sync.Mutex // serializes SCSI command sequences against this device
+	metrics deviceMetrics
+}
+
+var (
+	collectorsMu sync.RWMutex
+	collectors   []*deviceCollector
+)
+
+// runServe implements the `serve` subcommand: poll a set of configured
+// devices on a schedule and expose their status as Prometheus metrics.
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to a JSON config file listing devices to poll")
+	listen := fs.String("listen", ":9800", "Address to serve /metrics on")
+	fs.BoolVar(&verbose, "verbose", verbose, "Enable verbose output")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s serve --config=devices.json [--listen=:9800]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *configPath == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	config, err := loadServeConfig(*configPath)
+	if err != nil {
+		fmt.Printf("Failed to load config %s: %v\n", *configPath, err)
+		os.Exit(1)
+	}
+	if len(config.Devices) == 0 {
+		fmt.Printf("Config %s lists no devices\n", *configPath)
+		os.Exit(1)
+	}
+
+	collectorsMu.Lock()
+	for _, dc := range config.Devices {
+		c := &deviceCollector{config: dc, metrics: deviceMetrics{commandErrorCounts: map[string]int64{}}}
+		collectors = append(collectors, c)
+		go c.pollLoop()
+	}
+	collectorsMu.Unlock()
+
+	http.HandleFunc("/metrics", handleMetrics)
+	fmt.Printf("Serving Prometheus metrics for %d device(s) on %s/metrics\n", len(config.Devices), *listen)
+	if err := http.ListenAndServe(*listen, nil); err != nil {
+		fmt.Printf("Server failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func loadServeConfig(path string) (ServeConfig, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ServeConfig{}, err
+	}
+	defer file.Close()
+
+	var config ServeConfig
+	if err := json.NewDecoder(file).Decode(&config); err != nil {
+		return ServeConfig{}, fmt.Errorf("invalid config: %w", err)
+	}
+	return config, nil
+}
+
+// pollLoop re-reads this device's temperature, TapeAlert flags, and lifetime
+// MAM counters on config.IntervalSeconds, holding the collector's mutex for
+// the duration of each round so a slow scrape never overlaps a poll.
+func (c *deviceCollector) pollLoop() {
+	interval := time.Duration(c.config.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 60 * time.Second
+	}
+
+	for {
+		c.poll()
+		time.Sleep(interval)
+	}
+}
+
+func (c *deviceCollector) poll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	transport, err := OpenTransport(c.config.Path)
+	if err != nil {
+		c.recordError(err)
+		return
+	}
+	defer transport.Close()
+
+	if params, err := readLogPage(transport, LOG_PAGE_TEMPERATURE); err != nil {
+		c.recordError(err)
+	} else {
+		c.metrics.maxTemperatureC = float64(decodeTemperaturePage(params).CurrentCelsius)
+	}
+
+	if params, err := readLogPage(transport, LOG_PAGE_TAPE_USAGE); err != nil {
+		c.recordError(err)
+	} else {
+		usage := decodeTapeUsagePage(params)
+		c.metrics.lifetimeMBWritten = usage.MegabytesWritten
+		c.metrics.lifetimeMBRead = usage.MegabytesRead
+	}
+
+	if params, err := readLogPage(transport, LOG_PAGE_TAPE_ALERT); err != nil {
+		c.recordError(err)
+	} else {
+		c.metrics.tapeAlertFlags = activeTapeAlertFlags(params)
+	}
+
+	if attrs, err := readAttributes(transport); err == nil {
+		for _, a := range attrs {
+			if a.ID == MAM_SERIAL_NUMBER {
+				c.metrics.serial = a.Value
+			}
+		}
+	}
+}
+
+// recordError increments the error counter for err's sense key (or
+// "unknown" if err isn't a decoded *Error).
+func (c *deviceCollector) recordError(err error) {
+	if verbose {
+		fmt.Printf("Poll of %s failed: %v\n", c.config.Path, err)
+	}
+	senseKey := "unknown"
+	if se, ok := err.(*Error); ok {
+		senseKey = senseKeyNames[se.Info.SenseKey]
+		if senseKey == "" {
+			senseKey = fmt.Sprintf("0x%X", se.Info.SenseKey)
+		}
+	}
+	c.metrics.commandErrorCounts[senseKey]++
+}
+
+// handleMetrics writes every collector's cached metrics in Prometheus text
+// exposition format.
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	collectorsMu.RLock()
+	defer collectorsMu.RUnlock()
+
+	fmt.Fprintln(w, "# HELP lto_max_temperature_celsius Current drive temperature in degrees Celsius")
+	fmt.Fprintln(w, "# TYPE lto_max_temperature_celsius gauge")
+	for _, c := range collectors {
+		c.mu.Lock()
+		fmt.Fprintf(w, "lto_max_temperature_celsius{device=%q,serial=%q} %g\n", c.config.Path, c.metrics.serial, c.metrics.maxTemperatureC)
+		c.mu.Unlock()
+	}
+
+	fmt.Fprintln(w, "# HELP lto_lifetime_bytes_written Lifetime megabytes written to the loaded cartridge")
+	fmt.Fprintln(w, "# TYPE lto_lifetime_bytes_written gauge")
+	for _, c := range collectors {
+		c.mu.Lock()
+		fmt.Fprintf(w, "lto_lifetime_bytes_written{device=%q,serial=%q} %d\n", c.config.Path, c.metrics.serial, c.metrics.lifetimeMBWritten*1024*1024)
+		c.mu.Unlock()
+	}
+
+	fmt.Fprintln(w, "# HELP lto_tapealert_flag Active TapeAlert flags, one series per set flag")
+	fmt.Fprintln(w, "# TYPE lto_tapealert_flag gauge")
+	for _, c := range collectors {
+		c.mu.Lock()
+		for _, flag := range c.metrics.tapeAlertFlags {
+			fmt.Fprintf(w, "lto_tapealert_flag{device=%q,flag=%q,severity=%q} 1\n", c.config.Path, metricName(flag.Name), flag.Severity)
+		}
+		c.mu.Unlock()
+	}
+
+	fmt.Fprintln(w, "# HELP lto_command_errors_total SCSI command failures, by sense key")
+	fmt.Fprintln(w, "# TYPE lto_command_errors_total counter")
+	for _, c := range collectors {
+		c.mu.Lock()
+		keys := make([]string, 0, len(c.metrics.commandErrorCounts))
+		for k := range c.metrics.commandErrorCounts {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(w, "lto_command_errors_total{device=%q,sense_key=%q} %d\n", c.config.Path, k, c.metrics.commandErrorCounts[k])
+		}
+		c.mu.Unlock()
+	}
+}
+
+// metricName turns a TapeAlert flag's display name into a Prometheus-style
+// label value, e.g. "Cleaning required" -> "cleaning_required".
+func metricName(name string) string {
+	out := make([]byte, 0, len(name))
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			out = append(out, byte(r))
+		case r >= 'A' && r <= 'Z':
+			out = append(out, byte(r-'A'+'a'))
+		default:
+			if len(out) > 0 && out[len(out)-1] != '_' {
+				out = append(out, '_')
+			}
+		}
+	}
+	for len(out) > 0 && out[len(out)-1] == '_' {
+		out = out[:len(out)-1]
+	}
+	return string(out)
+}
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestMetricName(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"simple words", "Cleaning required", "cleaning_required"},
+		{"already lower", "snapped tape", "snapped_tape"},
+		{"punctuation collapses to one underscore", "Hard error!!", "hard_error"},
+		{"leading non-alnum trimmed", "!!Load failure", "load_failure"},
+		{"trailing non-alnum trimmed", "WORM medium---", "worm_medium"},
+		{"digits kept", "Read warning 2", "read_warning_2"},
+		{"empty", "", ""},
+	}
+	for _, c := range cases {
+		if got := metricName(c.in); got != c.want {
+			t.Errorf("%s: metricName(%q) = %q, want %q", c.name, c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// LOG_PAGE_TAPE_ALERT is the standardized TapeAlert log page (SSC), which
+// reports up to 64 flags as one log parameter per flag.
+const LOG_PAGE_TAPE_ALERT = 0x2E
+
+// tapeAlertSeverity classifies a TapeAlert flag's urgency, per the SSC spec.
+type tapeAlertSeverity string
+
+const (
+	SeverityInformational tapeAlertSeverity = "Informational"
+	SeverityWarning       tapeAlertSeverity = "Warning"
+	SeverityCritical      tapeAlertSeverity = "Critical"
+)
+
+// TapeAlertFlag describes one of the 64 standardized TapeAlert conditions.
+type TapeAlertFlag struct {
+	Parameter uint16
+	Name      string
+	Severity  tapeAlertSeverity
+}
+
+// tapeAlertFlags is indexed by parameter code (0x0001-0x0040), the canonical
+// set of TapeAlert flags defined by the SSC TapeAlert standard.
+var tapeAlertFlags = map[uint16]TapeAlertFlag{
+	0x01: {0x01, "Read warning", SeverityWarning},
+	0x02: {0x02, "Write warning", SeverityWarning},
+	0x03: {0x03, "Hard error", SeverityCritical},
+	0x04: {0x04, "Media life", SeverityWarning},
+	0x05: {0x05, "Read failure", SeverityCritical},
+	0x06: {0x06, "Write failure", SeverityCritical},
+	0x07: {0x07, "Media life (near end)", SeverityWarning},
+	0x08: {0x08, "Not data grade", SeverityWarning},
+	0x09: {0x09, "Write protect", SeverityInformational},
+	0x0A: {0x0A, "No removal", SeverityInformational},
+	0x0B: {0x0B, "Cleaning media", SeverityInformational},
+	0x0C: {0x0C, "Unsupported format", SeverityCritical},
+	0x0D: {0x0D, "Recoverable mechanical cartridge failure", SeverityWarning},
+	0x0E: {0x0E, "Unrecoverable mechanical cartridge failure", SeverityCritical},
+	0x0F: {0x0F, "Memory chip in cartridge failure", SeverityWarning},
+	0x10: {0x10, "Forced eject", SeverityCritical},
+	0x11: {0x11, "Read only format", SeverityWarning},
+	0x12: {0x12, "Tape directory corrupted on load", SeverityWarning},
+	0x13: {0x13, "Nearing media life", SeverityWarning},
+	0x14: {0x14, "Cleaning required", SeverityWarning},
+	0x15: {0x15, "Cleaning requested", SeverityInformational},
+	0x16: {0x16, "Expired cleaning media", SeverityWarning},
+	0x17: {0x17, "Invalid cleaning tape", SeverityWarning},
+	0x18: {0x18, "Retension requested", SeverityInformational},
+	0x19: {0x19, "Dual port interface error", SeverityWarning},
+	0x1A: {0x1A, "Cooling fan failure", SeverityWarning},
+	0x1B: {0x1B, "Power supply failure", SeverityWarning},
+	0x1C: {0x1C, "Power consumption", SeverityWarning},
+	0x1D: {0x1D, "Drive maintenance", SeverityWarning},
+	0x1E: {0x1E, "Hardware A", SeverityCritical},
+	0x1F: {0x1F, "Hardware B", SeverityWarning},
+	0x20: {0x20, "Interface", SeverityWarning},
+	0x21: {0x21, "Eject media", SeverityCritical},
+	0x22: {0x22, "Microcode update fail", SeverityWarning},
+	0x23: {0x23, "Drive humidity", SeverityWarning},
+	0x24: {0x24, "Drive temperature", SeverityWarning},
+	0x25: {0x25, "Drive voltage", SeverityWarning},
+	0x26: {0x26, "Predictive failure", SeverityCritical},
+	0x27: {0x27, "Diagnostics required", SeverityWarning},
+	0x28: {0x28, "Loader hardware A", SeverityCritical},
+	0x29: {0x29, "Loader stray tape", SeverityWarning},
+	0x2A: {0x2A, "Loader hardware B", SeverityWarning},
+	0x2B: {0x2B, "Loader door open", SeverityWarning},
+	0x2C: {0x2C, "Loader magazine removed", SeverityInformational},
+	0x2D: {0x2D, "Loader diagnostics required", SeverityWarning},
+	0x2E: {0x2E, "Lost statistics", SeverityInformational},
+	0x2F: {0x2F, "Tape directory invalid at unload", SeverityWarning},
+	0x30: {0x30, "Tape system area write failure", SeverityCritical},
+	0x31: {0x31, "Tape system area read failure", SeverityCritical},
+	0x32: {0x32, "No start of data", SeverityCritical},
+	0x33: {0x33, "Loading failure", SeverityCritical},
+	0x34: {0x34, "Unrecoverable unload failure", SeverityCritical},
+	0x35: {0x35, "Automation interface failure", SeverityWarning},
+	0x36: {0x36, "Firmware failure", SeverityCritical},
+	0x37: {0x37, "WORM medium — integrity check failed", SeverityCritical},
+	0x38: {0x38, "WORM medium — overwrite attempted", SeverityWarning},
+	0x39: {0x39, "Encryption policy violation", SeverityCritical},
+	0x3A: {0x3A, "Unable to decrypt data", SeverityCritical},
+	0x3B: {0x3B, "Unable to encrypt data", SeverityCritical},
+	0x3C: {0x3C, "Snapped tape", SeverityCritical},
+	0x3D: {0x3D, "Memory chip in cartridge failure", SeverityWarning},
+	0x3E: {0x3E, "Forced eject", SeverityCritical},
+	0x3F: {0x3F, "Learn cartridge failed", SeverityWarning},
+	0x40: {0x40, "Lost statistics (cartridge)", SeverityInformational},
+}
+
+// runTapeAlert implements the `tapealert` subcommand: read log page 0x2E,
+// report set flags, and optionally re-read it for drives that self-clear on
+// read.
+func runTapeAlert(args []string) {
+	fs := flag.NewFlagSet("tapealert", flag.ExitOnError)
+	clear := fs.Bool("clear", false, "Re-read the page once more since some drives self-clear TapeAlert flags on read")
+	jsonOut := fs.Bool("json", false, "Print the set flags as JSON")
+	fs.BoolVar(&verbose, "verbose", verbose, "Enable verbose output")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s tapealert [--clear] [--json] <scsi_device>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	device := fs.Arg(0)
+
+	transport, err := OpenTransport(device)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+	defer transport.Close()
+
+	params, err := readLogPage(transport, LOG_PAGE_TAPE_ALERT)
+	if err != nil {
+		fmt.Printf("Failed to read TapeAlert log page: %v\n", err)
+		os.Exit(1)
+	}
+	if *clear {
+		// Some drives only clear TapeAlert flags once the page has been
+		// read; issue a second read so the caller sees the latched state.
+		if _, err := readLogPage(transport, LOG_PAGE_TAPE_ALERT); err != nil {
+			fmt.Printf("Failed to re-read TapeAlert log page: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	active := activeTapeAlertFlags(params)
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(active)
+	} else if len(active) == 0 {
+		fmt.Println("No TapeAlert flags set.")
+	} else {
+		for _, f := range active {
+			fmt.Printf("[%s] %s (parameter 0x%04X)\n", f.Severity, f.Name, f.Parameter)
+		}
+	}
+
+	os.Exit(tapeAlertExitCode(active))
+}
+
+// activeTapeAlertFlags returns the TapeAlertFlag entries whose parameter is
+// set (each parameter's single value byte's low bit is 1), in parameter
+// order.
+func activeTapeAlertFlags(params []logParameter) []TapeAlertFlag {
+	var active []TapeAlertFlag
+	for _, p := range params {
+		if len(p.Value) < 1 || p.Value[0]&0x01 == 0 {
+			continue
+		}
+		flag, ok := tapeAlertFlags[p.Code]
+		if !ok {
+			// Fail safe: an unrecognized-but-set flag defaults to Warning so
+			// tapeAlertExitCode still signals trouble to cron/monitoring,
+			// rather than silently passing as Informational.
+			flag = TapeAlertFlag{Parameter: p.Code, Name: fmt.Sprintf("Unknown flag 0x%04X", p.Code), Severity: SeverityWarning}
+		}
+		active = append(active, flag)
+	}
+	return active
+}
+
+// tapeAlertExitCode returns non-zero when any active flag is Warning or
+// Critical, so this subcommand can be used directly from cron/monitoring.
+func tapeAlertExitCode(active []TapeAlertFlag) int {
+	for _, f := range active {
+		if f.Severity == SeverityWarning || f.Severity == SeverityCritical {
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+func TestActiveTapeAlertFlags(t *testing.T) {
+	params := []logParameter{
+		{Code: 0x14, Value: []byte{0x01}}, // Cleaning required: set
+		{Code: 0x01, Value: []byte{0x00}}, // Read warning: clear
+		{Code: 0x3C, Value: []byte{0x01}}, // Snapped tape: set
+		{Code: 0x50, Value: []byte{0x01}}, // unrecognized flag: set
+	}
+
+	got := activeTapeAlertFlags(params)
+	if len(got) != 3 {
+		t.Fatalf("activeTapeAlertFlags() returned %d flags, want 3: %+v", len(got), got)
+	}
+	if got[0].Name != "Cleaning required" || got[0].Severity != SeverityWarning {
+		t.Errorf("got[0] = %+v, want Cleaning required/Warning", got[0])
+	}
+	if got[1].Name != "Snapped tape" || got[1].Severity != SeverityCritical {
+		t.Errorf("got[1] = %+v, want Snapped tape/Critical", got[1])
+	}
+	if got[2].Parameter != 0x50 || got[2].Severity != SeverityWarning {
+		t.Errorf("got[2] = %+v, want parameter 0x50/Warning fail-safe fallback", got[2])
+	}
+}
+
+func TestActiveTapeAlertFlagsNoneSet(t *testing.T) {
+	params := []logParameter{
+		{Code: 0x01, Value: []byte{0x00}},
+		{Code: 0x02, Value: []byte{}},
+	}
+	if got := activeTapeAlertFlags(params); len(got) != 0 {
+		t.Fatalf("activeTapeAlertFlags() = %+v, want none", got)
+	}
+}
+
+func TestTapeAlertExitCode(t *testing.T) {
+	cases := []struct {
+		name   string
+		active []TapeAlertFlag
+		want   int
+	}{
+		{"none", nil, 0},
+		{"informational only", []TapeAlertFlag{{Severity: SeverityInformational}}, 0},
+		{"warning", []TapeAlertFlag{{Severity: SeverityWarning}}, 1},
+		{"critical", []TapeAlertFlag{{Severity: SeverityCritical}}, 1},
+	}
+	for _, c := range cases {
+		if got := tapeAlertExitCode(c.active); got != c.want {
+			t.Errorf("%s: tapeAlertExitCode() = %d, want %d", c.name, got, c.want)
+		}
+	}
+}
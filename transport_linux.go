@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// ioctl constants for SCSI generic (SG) operations
+const (
+	sgIO           = 0x2285
+	sgDxferToDev   = -2 // Direction of data transfer (to device)
+	sgDxferFromDev = 1  // Direction of data transfer (from device)
+)
+
+// sgIOHeader mirrors the kernel's struct sg_io_hdr used to send SCSI
+// commands through the SG_IO ioctl.
+type sgIOHeader struct {
+	interface_id    int32   // Identifier for the interface, typically set to 'S' for SCSI
+	dxfer_direction int32   // Data transfer direction: -2 for host to device, 1 for device to host
+	cmd_len         uint8   // Length of the SCSI command descriptor block (CDB) in bytes
+	mx_sb_len       uint8   // Maximum length of the sense buffer, used for error reporting
+	iovec_count     uint16  // Count for scatter-gather lists, set to 0 if not used
+	dxfer_len       uint32  // Length of the data to be transferred in bytes
+	dxferp          uintptr // Pointer to the data buffer for data transfer (input or output)
+	cmdp            uintptr // Pointer to the command descriptor block (CDB)
+	sbp             uintptr // Pointer to the sense buffer, which stores error information
+	timeout         uint32  // Command timeout in milliseconds
+	flags           uint32  // Additional flags for command execution (e.g., blocking, etc.)
+	pack_id         int32   // Packet ID used to track the command
+	usr_ptr         uintptr // User-defined pointer, often used for additional data tracking
+	status          uint8   // Status byte returned from the device, indicating success or error
+	masked_status   uint8   // Internal masked status, used by the driver
+	msg_status      uint8   // Message byte returned by the device
+	sb_len_wr       uint8   // Actual length of the sense buffer written by the device
+	host_status     uint16  // Host-specific status code, set by the driver
+	driver_status   uint16  // Driver-specific status code, set by the driver
+	resid           int32   // Residual byte count, indicating remaining data not transferred
+	duration        uint32  // Duration the command took to execute, in milliseconds
+	info            uint32  // Additional information about the command, such as retries or errors
+}
+
+// linuxTransport executes SCSI commands via the Linux SG_IO ioctl against an
+// open SCSI generic (/dev/sg*) device node.
+type linuxTransport struct {
+	file *os.File
+}
+
+// OpenTransport opens a Linux SCSI generic device, e.g. /dev/sg4.
+func OpenTransport(device string) (Transport, error) {
+	file, err := os.OpenFile(device, os.O_RDWR, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open device %s: %w", device, err)
+	}
+	return &linuxTransport{file: file}, nil
+}
+
+func (t *linuxTransport) Execute(cmd, dataOut, dataIn []byte, timeout time.Duration) (*Response, error) {
+	// Allocate sense buffer for error reporting
+	sense := make([]byte, 32)
+
+	direction := int32(sgDxferFromDev)
+	if len(dataOut) > 0 {
+		direction = sgDxferToDev
+	}
+
+	// Prepare the sg_io_hdr
+	header := sgIOHeader{
+		interface_id:    'S',
+		dxfer_direction: direction,
+		cmd_len:         uint8(len(cmd)),
+		mx_sb_len:       uint8(len(sense)),
+		dxfer_len:       uint32(len(dataOut) + len(dataIn)),
+		cmdp:            uintptr(unsafe.Pointer(&cmd[0])),
+		sbp:             uintptr(unsafe.Pointer(&sense[0])),
+		timeout:         uint32(timeout / time.Millisecond), // Convert timeout to milliseconds
+	}
+
+	// Set up data transfer pointers if needed
+	if len(dataOut) > 0 {
+		header.dxferp = uintptr(unsafe.Pointer(&dataOut[0]))
+	}
+	if len(dataIn) > 0 {
+		header.dxferp = uintptr(unsafe.Pointer(&dataIn[0]))
+	}
+
+	if verbose {
+		fmt.Printf("Executing ioctl with cmd=%s, dataOut=%s, dataInLen=%d\n", formatBytes(cmd), formatBytes(dataOut), len(dataIn))
+	}
+	if err := ioctl(int(t.file.Fd()), sgIO, uintptr(unsafe.Pointer(&header))); err != nil {
+		return nil, fmt.Errorf("command failed: %w", err)
+	}
+
+	return &Response{
+		Status:   header.status,
+		Sense:    sense[:header.sb_len_wr],
+		Duration: time.Duration(header.duration) * time.Millisecond,
+	}, nil
+}
+
+func (t *linuxTransport) Close() error {
+	return t.file.Close()
+}
+
+// ioctl issues a raw ioctl syscall, used by Execute to drive SG_IO.
+func ioctl(fd int, request int, arg uintptr) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(request), arg)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
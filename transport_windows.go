@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// IOCTL_SCSI_PASS_THROUGH_DIRECT and the GENERIC_READ/WRITE access flags
+// needed to open a tape or changer device handle for it.
+const (
+	ioctlScsiPassThroughDirect = 0x4D014
+	genericRead                = 0x80000000
+	genericWrite               = 0x40000000
+	fileShareReadWrite         = 0x00000003
+	openExisting               = 3
+
+	sptwdDataIn  = 1
+	sptwdDataOut = 0
+)
+
+// scsiPassThroughDirect mirrors Windows' SCSI_PASS_THROUGH_DIRECT struct
+// (winioctl.h), used to submit a CDB via IOCTL_SCSI_PASS_THROUGH_DIRECT.
+type scsiPassThroughDirect struct {
+	length             uint16
+	scsiStatus         uint8
+	pathID             uint8
+	targetID           uint8
+	lun                uint8
+	cdbLength          uint8
+	senseInfoLength    uint8
+	dataIn             uint8
+	dataTransferLength uint32
+	timeOutValue       uint32
+	dataBuffer         uintptr
+	senseInfoOffset    uint32
+	cdb                [16]byte
+}
+
+// scsiPassThroughDirectWithSense bundles the SCSI_PASS_THROUGH_DIRECT
+// request with its trailing sense buffer, the layout
+// IOCTL_SCSI_PASS_THROUGH_DIRECT expects when senseInfoOffset points past
+// the fixed struct.
+type scsiPassThroughDirectWithSense struct {
+	spt       scsiPassThroughDirect
+	senseData [32]byte
+}
+
+// windowsTransport executes SCSI commands via IOCTL_SCSI_PASS_THROUGH_DIRECT
+// against an open tape device handle, e.g. \\.\Tape0.
+type windowsTransport struct {
+	handle syscall.Handle
+}
+
+// OpenTransport opens a Windows tape device, e.g. \\.\Tape0.
+func OpenTransport(device string) (Transport, error) {
+	path, err := syscall.UTF16PtrFromString(device)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open device %s: %w", device, err)
+	}
+
+	handle, err := syscall.CreateFile(path, genericRead|genericWrite, fileShareReadWrite, nil, openExisting, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open device %s: %w", device, err)
+	}
+
+	return &windowsTransport{handle: handle}, nil
+}
+
+func (t *windowsTransport) Execute(cmd, dataOut, dataIn []byte, timeout time.Duration) (*Response, error) {
+	var req scsiPassThroughDirectWithSense
+	req.spt.length = uint16(unsafe.Sizeof(req.spt))
+	req.spt.cdbLength = uint8(len(cmd))
+	copy(req.spt.cdb[:], cmd)
+	req.spt.senseInfoLength = uint8(len(req.senseData))
+	req.spt.senseInfoOffset = uint32(unsafe.Offsetof(req.senseData))
+	req.spt.timeOutValue = uint32(timeout / time.Second)
+
+	data := dataOut
+	req.spt.dataIn = sptwdDataOut
+	if len(dataIn) > 0 {
+		data = dataIn
+		req.spt.dataIn = sptwdDataIn
+	}
+	req.spt.dataTransferLength = uint32(len(data))
+	if len(data) > 0 {
+		req.spt.dataBuffer = uintptr(unsafe.Pointer(&data[0]))
+	}
+
+	if verbose {
+		fmt.Printf("Executing IOCTL_SCSI_PASS_THROUGH_DIRECT with cmd=%s, dataLen=%d\n", formatBytes(cmd), len(data))
+	}
+
+	var bytesReturned uint32
+	reqLen := uint32(unsafe.Sizeof(req))
+	if err := syscall.DeviceIoControl(t.handle, ioctlScsiPassThroughDirect,
+		(*byte)(unsafe.Pointer(&req)), reqLen,
+		(*byte)(unsafe.Pointer(&req)), reqLen,
+		&bytesReturned, nil); err != nil {
+		return nil, fmt.Errorf("command failed: %w", err)
+	}
+
+	return &Response{Status: req.spt.scsiStatus, Sense: req.senseData[:]}, nil
+}
+
+func (t *windowsTransport) Close() error {
+	return syscall.CloseHandle(t.handle)
+}